@@ -0,0 +1,110 @@
+package cloudflare
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	log "github.com/sourcegraph-ce/logrus"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// originCARootCertificateURLs maps the supported key algorithms to the
+// well-known, publicly documented URLs Cloudflare publishes its Origin CA
+// root certificates at.
+var originCARootCertificateURLs = map[string]string{
+	"rsa": "https://developers.cloudflare.com/ssl/static/origin_ca_rsa_root.pem",
+	"ecc": "https://developers.cloudflare.com/ssl/static/origin_ca_ecc_root.pem",
+}
+
+// originCARootCertificateHTTPClient is used to fetch the root certificates
+// with a bounded timeout so a stalled connection can't hang a plan/apply.
+var originCARootCertificateHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+var (
+	originCARootCertificateCacheMu sync.Mutex
+	originCARootCertificateCache   = map[string]string{}
+)
+
+func dataSourceCloudflareOriginCARootCertificate() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCloudflareOriginCARootCertificateRead,
+
+		Schema: map[string]*schema.Schema{
+			"algorithm": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"rsa", "ecc"}, false),
+			},
+			"cert_pem": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceCloudflareOriginCARootCertificateRead(d *schema.ResourceData, meta interface{}) error {
+	algorithm := d.Get("algorithm").(string)
+
+	certPEM, err := fetchOriginCARootCertificate(algorithm)
+	if err != nil {
+		return err
+	}
+
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return fmt.Errorf("Error decoding Origin CA root certificate: invalid PEM data")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("Error parsing Origin CA root certificate: %s", err)
+	}
+
+	d.SetId(cert.SerialNumber.String())
+	d.Set("cert_pem", certPEM)
+
+	return nil
+}
+
+// fetchOriginCARootCertificate returns the PEM-encoded Origin CA root
+// certificate for algorithm, fetching it at most once per provider process
+// and caching the result in-memory since the root certificates are static.
+func fetchOriginCARootCertificate(algorithm string) (string, error) {
+	originCARootCertificateCacheMu.Lock()
+	defer originCARootCertificateCacheMu.Unlock()
+
+	if certPEM, ok := originCARootCertificateCache[algorithm]; ok {
+		return certPEM, nil
+	}
+
+	url := originCARootCertificateURLs[algorithm]
+
+	log.Printf("[DEBUG] Fetching Cloudflare Origin CA %s root certificate from %s", algorithm, url)
+
+	resp, err := originCARootCertificateHTTPClient.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("Error fetching Origin CA root certificate: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Error fetching Origin CA root certificate: unexpected status %s", resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("Error reading Origin CA root certificate response: %s", err)
+	}
+
+	certPEM := string(body)
+	originCARootCertificateCache[algorithm] = certPEM
+
+	return certPEM, nil
+}
@@ -0,0 +1,47 @@
+package cloudflare
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const testOriginCARootCertificatePEM = "-----BEGIN CERTIFICATE-----\ntest-fixture-not-a-real-certificate\n-----END CERTIFICATE-----\n"
+
+func TestFetchOriginCARootCertificate_Caches(t *testing.T) {
+	var requests int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(testOriginCARootCertificatePEM))
+	}))
+	defer ts.Close()
+
+	const algorithm = "test-cache-algorithm"
+	originCARootCertificateURLs[algorithm] = ts.URL
+	defer delete(originCARootCertificateURLs, algorithm)
+	defer func() {
+		originCARootCertificateCacheMu.Lock()
+		delete(originCARootCertificateCache, algorithm)
+		originCARootCertificateCacheMu.Unlock()
+	}()
+
+	first, err := fetchOriginCARootCertificate(algorithm)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	second, err := fetchOriginCARootCertificate(algorithm)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if first != second {
+		t.Fatalf("expected cached certificate to match first fetch")
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("expected exactly 1 HTTP request, got %d", got)
+	}
+}
@@ -1,7 +1,14 @@
 package cloudflare
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
 	"fmt"
 	log "github.com/sourcegraph-ce/logrus"
@@ -21,18 +28,47 @@ func resourceCloudflareOriginCACertificate() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: resourceCloudflareOriginCACertificateCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"certificate": {
 				Type:     schema.TypeString,
 				Computed: true,
+				ForceNew: true,
 			},
 			"csr": {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
+				Computed:     true,
 				ForceNew:     true,
 				ValidateFunc: validateCSR,
 			},
+			"private_key_algorithm": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "RSA",
+				ValidateFunc: validation.StringInSlice([]string{"RSA", "ECDSA"}, false),
+			},
+			"private_key_bits": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      2048,
+				ValidateFunc: validation.IntInSlice([]int{2048, 4096}),
+			},
+			"private_key_ecdsa_curve": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "P256",
+				ValidateFunc: validation.StringInSlice([]string{"P224", "P256", "P384", "P521"}, false),
+			},
+			"private_key_pem": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
 			"expires_on": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -41,6 +77,7 @@ func resourceCloudflareOriginCACertificate() *schema.Resource {
 				Type:     schema.TypeSet,
 				Required: true,
 				ForceNew: true,
+				MinItems: 1,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -57,12 +94,148 @@ func resourceCloudflareOriginCACertificate() *schema.Resource {
 				ForceNew:     true,
 				ValidateFunc: validation.IntInSlice([]int{7, 30, 90, 365, 730, 1095, 5475}),
 			},
+			"min_days_remaining": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Number of days prior to expiration to trigger a renewal of the certificate. A value of 0 disables the check.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"api_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+				Description: "Scoped API token (Zone -> SSL and Certificates -> Edit) to use instead of the account-wide Origin CA Key configured on the provider.",
+			},
+			"serial_number": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"sha256_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"sha1_fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"issuer": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"subject": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"not_before": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"dns_names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 		},
 	}
 }
 
+// setOriginCACertificateParsedAttributes PEM-decodes and parses certPEM,
+// populating the computed X.509 metadata attributes on d so that downstream
+// resources (monitoring, PKI storage, etc.) can consume the serial number
+// and fingerprints without reaching for an external data source.
+func setOriginCACertificateParsedAttributes(d *schema.ResourceData, certPEM string) error {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return fmt.Errorf("Error decoding certificate: invalid PEM data")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("Error parsing certificate: %s", err)
+	}
+
+	sha256Fingerprint := sha256.Sum256(cert.Raw)
+	sha1Fingerprint := sha1.Sum(cert.Raw)
+
+	d.Set("serial_number", cert.SerialNumber.String())
+	d.Set("sha256_fingerprint", fmt.Sprintf("%x", sha256Fingerprint))
+	d.Set("sha1_fingerprint", fmt.Sprintf("%x", sha1Fingerprint))
+	d.Set("issuer", cert.Issuer.String())
+	d.Set("subject", cert.Subject.String())
+	d.Set("not_before", cert.NotBefore.Format(time.RFC3339))
+	d.Set("dns_names", cert.DNSNames)
+
+	return nil
+}
+
+// resourceCloudflareOriginCACertificateClient returns the Cloudflare API
+// client to use for this resource. When api_token is set it builds a client
+// scoped to that token, reusing the provider-configured client's BaseURL
+// (the only part of its configuration cloudflare-go exposes on *API) so a
+// test or self-hosted BaseURL override still applies; otherwise it falls
+// back to the Origin CA Key configured on the provider so existing
+// configurations keep working.
+func resourceCloudflareOriginCACertificateClient(d *schema.ResourceData, meta interface{}) (*cloudflare.API, error) {
+	base := meta.(*cloudflare.API)
+
+	apiToken, ok := d.GetOk("api_token")
+	if !ok {
+		return base, nil
+	}
+
+	client, err := cloudflare.NewWithAPIToken(apiToken.(string))
+	if err != nil {
+		return nil, fmt.Errorf("Error building scoped Cloudflare API client: %s", err)
+	}
+	client.BaseURL = base.BaseURL
+
+	return client, nil
+}
+
+// resourceCloudflareOriginCACertificateCustomizeDiff forces replacement of
+// the certificate once it has fewer than min_days_remaining days left before
+// expiry, so that a routine `terraform apply` reissues it rather than
+// requiring someone to remember to taint the resource.
+func resourceCloudflareOriginCACertificateCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	minDaysRemaining := d.Get("min_days_remaining").(int)
+	if minDaysRemaining <= 0 {
+		return nil
+	}
+
+	expiresOnRaw, ok := d.GetOk("expires_on")
+	if !ok {
+		return nil
+	}
+
+	expiresOn, err := time.Parse(time.RFC3339, expiresOnRaw.(string))
+	if err != nil {
+		return fmt.Errorf("error parsing expires_on %q: %s", expiresOnRaw.(string), err)
+	}
+
+	if originCACertificateWithinRenewalWindow(expiresOn, minDaysRemaining) {
+		log.Printf("[INFO] OriginCACertificate is within its renewal window, forcing renewal")
+		return d.SetNewComputed("certificate")
+	}
+
+	return nil
+}
+
+// originCACertificateWithinRenewalWindow reports whether expiresOn is less
+// than minDaysRemaining days away.
+func originCACertificateWithinRenewalWindow(expiresOn time.Time, minDaysRemaining int) bool {
+	daysRemaining := time.Until(expiresOn).Hours() / 24
+	return daysRemaining < float64(minDaysRemaining)
+}
+
 func resourceCloudflareOriginCACertificateCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*cloudflare.API)
+	client, err := resourceCloudflareOriginCACertificateClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	hostnames := []string{}
 	hostnamesRaw := d.Get("hostnames").(*schema.Set)
@@ -70,10 +243,26 @@ func resourceCloudflareOriginCACertificateCreate(d *schema.ResourceData, meta in
 		hostnames = append(hostnames, h.(string))
 	}
 
+	requestType := d.Get("request_type").(string)
+
+	csr, ok := d.GetOk("csr")
+	if !ok {
+		if requestType == "keyless-certificate" {
+			return fmt.Errorf("csr is required when request_type is %q: the private key for a keyless certificate must be generated and held by the external keyless server, not the provider", requestType)
+		}
+
+		generatedCSR, keyPEM, err := generateOriginCACSR(d, hostnames)
+		if err != nil {
+			return fmt.Errorf("Error generating private key and CSR: %s", err)
+		}
+		csr = generatedCSR
+		d.Set("private_key_pem", keyPEM)
+	}
+
 	certInput := cloudflare.OriginCACertificate{
-		CSR:         d.Get("csr").(string),
+		CSR:         csr.(string),
 		Hostnames:   hostnames,
-		RequestType: d.Get("request_type").(string),
+		RequestType: requestType,
 	}
 
 	requestValidity, ok := d.GetOk("requested_validity")
@@ -89,13 +278,22 @@ func resourceCloudflareOriginCACertificateCreate(d *schema.ResourceData, meta in
 	}
 
 	d.SetId(cert.ID)
+	d.Set("csr", csr)
 	d.Set("certificate", cert.Certificate)
 	d.Set("expires_on", cert.ExpiresOn.Format(time.RFC3339))
+
+	if err := setOriginCACertificateParsedAttributes(d, cert.Certificate); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func resourceCloudflareOriginCACertificateRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*cloudflare.API)
+	client, err := resourceCloudflareOriginCACertificateClient(d, meta)
+	if err != nil {
+		return err
+	}
 	certID := d.Id()
 	cert, err := client.OriginCertificate(certID)
 
@@ -126,16 +324,23 @@ func resourceCloudflareOriginCACertificateRead(d *schema.ResourceData, meta inte
 	d.Set("hostnames", hostnames)
 	d.Set("request_type", cert.RequestType)
 
+	if err := setOriginCACertificateParsedAttributes(d, cert.Certificate); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func resourceCloudflareOriginCACertificateDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*cloudflare.API)
+	client, err := resourceCloudflareOriginCACertificateClient(d, meta)
+	if err != nil {
+		return err
+	}
 	certID := d.Id()
 
 	log.Printf("[INFO] Revoking Cloudflare OriginCACertificate: id %s", certID)
 
-	_, err := client.RevokeOriginCertificate(certID)
+	_, err = client.RevokeOriginCertificate(certID)
 
 	if err != nil {
 		return fmt.Errorf("Error revoking Cloudflare OriginCACertificate: %s", err)
@@ -158,3 +363,76 @@ func validateCSR(v interface{}, k string) (ws []string, errors []error) {
 	}
 	return
 }
+
+// generateOriginCACSR generates a private key using the algorithm configured
+// on the resource and returns a PEM-encoded CSR for it alongside the
+// PEM-encoded private key, so that users aren't required to supply a
+// pre-baked CSR for every certificate.
+func generateOriginCACSR(d *schema.ResourceData, hostnames []string) (string, string, error) {
+	algorithm := d.Get("private_key_algorithm").(string)
+
+	template := x509.CertificateRequest{
+		Subject:            pkix.Name{CommonName: hostnames[0]},
+		DNSNames:           hostnames,
+		SignatureAlgorithm: x509.SHA256WithRSA,
+	}
+
+	var privateKeyPEM []byte
+	var csrDER []byte
+	var err error
+
+	switch algorithm {
+	case "ECDSA":
+		curve, curveErr := ecdsaCurveFromName(d.Get("private_key_ecdsa_curve").(string))
+		if curveErr != nil {
+			return "", "", curveErr
+		}
+
+		key, keyErr := ecdsa.GenerateKey(curve, rand.Reader)
+		if keyErr != nil {
+			return "", "", fmt.Errorf("error generating ECDSA private key: %s", keyErr)
+		}
+
+		keyBytes, marshalErr := x509.MarshalECPrivateKey(key)
+		if marshalErr != nil {
+			return "", "", fmt.Errorf("error marshaling ECDSA private key: %s", marshalErr)
+		}
+		privateKeyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes})
+
+		template.SignatureAlgorithm = x509.ECDSAWithSHA256
+		csrDER, err = x509.CreateCertificateRequest(rand.Reader, &template, key)
+	default:
+		bits := d.Get("private_key_bits").(int)
+
+		key, keyErr := rsa.GenerateKey(rand.Reader, bits)
+		if keyErr != nil {
+			return "", "", fmt.Errorf("error generating RSA private key: %s", keyErr)
+		}
+		privateKeyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+		csrDER, err = x509.CreateCertificateRequest(rand.Reader, &template, key)
+	}
+
+	if err != nil {
+		return "", "", fmt.Errorf("error creating certificate request: %s", err)
+	}
+
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+	return string(csrPEM), string(privateKeyPEM), nil
+}
+
+func ecdsaCurveFromName(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P224":
+		return elliptic.P224(), nil
+	case "P256":
+		return elliptic.P256(), nil
+	case "P384":
+		return elliptic.P384(), nil
+	case "P521":
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("unsupported private_key_ecdsa_curve %q", name)
+	}
+}
@@ -0,0 +1,243 @@
+package cloudflare
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestGenerateOriginCACSR_RSA(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCloudflareOriginCACertificate().Schema, map[string]interface{}{
+		"private_key_algorithm": "RSA",
+		"private_key_bits":      2048,
+	})
+
+	csrPEM, keyPEM, err := generateOriginCACSR(d, []string{"example.com", "www.example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		t.Fatalf("expected a PEM encoded certificate request, got: %s", csrPEM)
+	}
+
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		t.Fatalf("error parsing generated CSR: %s", err)
+	}
+
+	if csr.Subject.CommonName != "example.com" {
+		t.Fatalf("expected CommonName %q, got %q", "example.com", csr.Subject.CommonName)
+	}
+
+	keyBlock, _ := pem.Decode([]byte(keyPEM))
+	if keyBlock == nil || keyBlock.Type != "RSA PRIVATE KEY" {
+		t.Fatalf("expected a PEM encoded RSA private key, got: %s", keyPEM)
+	}
+}
+
+func TestGenerateOriginCACSR_ECDSA(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCloudflareOriginCACertificate().Schema, map[string]interface{}{
+		"private_key_algorithm":   "ECDSA",
+		"private_key_ecdsa_curve": "P256",
+	})
+
+	csrPEM, keyPEM, err := generateOriginCACSR(d, []string{"example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	keyBlock, _ := pem.Decode([]byte(keyPEM))
+	if keyBlock == nil || keyBlock.Type != "EC PRIVATE KEY" {
+		t.Fatalf("expected a PEM encoded EC private key, got: %s", keyPEM)
+	}
+
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		t.Fatalf("expected a PEM encoded certificate request, got: %s", csrPEM)
+	}
+}
+
+func TestGenerateOriginCACSR_UnsupportedCurve(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCloudflareOriginCACertificate().Schema, map[string]interface{}{
+		"private_key_algorithm":   "ECDSA",
+		"private_key_ecdsa_curve": "P999",
+	})
+
+	if _, _, err := generateOriginCACSR(d, []string{"example.com"}); err == nil {
+		t.Fatal("expected an error for an unsupported ECDSA curve, got nil")
+	}
+}
+
+func TestOriginCACertificateWithinRenewalWindow(t *testing.T) {
+	now := time.Now()
+
+	cases := map[string]struct {
+		ExpiresOn        time.Time
+		MinDaysRemaining int
+		Expected         bool
+	}{
+		"well outside the window": {
+			ExpiresOn:        now.Add(90 * 24 * time.Hour),
+			MinDaysRemaining: 30,
+			Expected:         false,
+		},
+		"inside the window": {
+			ExpiresOn:        now.Add(10 * 24 * time.Hour),
+			MinDaysRemaining: 30,
+			Expected:         true,
+		},
+		"already expired": {
+			ExpiresOn:        now.Add(-24 * time.Hour),
+			MinDaysRemaining: 30,
+			Expected:         true,
+		},
+	}
+
+	for tn, tc := range cases {
+		got := originCACertificateWithinRenewalWindow(tc.ExpiresOn, tc.MinDaysRemaining)
+		if got != tc.Expected {
+			t.Errorf("%s: expected %v, got %v", tn, tc.Expected, got)
+		}
+	}
+}
+
+func TestResourceCloudflareOriginCACertificateCreate_KeylessRequiresCSR(t *testing.T) {
+	client, err := cloudflare.New("somekey", "someemail")
+	if err != nil {
+		t.Fatalf("error building client: %s", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceCloudflareOriginCACertificate().Schema, map[string]interface{}{
+		"hostnames":    []interface{}{"example.com"},
+		"request_type": "keyless-certificate",
+	})
+
+	err = resourceCloudflareOriginCACertificateCreate(d, client)
+	if err == nil {
+		t.Fatal("expected an error when csr is omitted for a keyless-certificate request")
+	}
+
+	if !strings.Contains(err.Error(), "csr is required") {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestResourceCloudflareOriginCACertificateClient_FallsBackWithoutAPIToken(t *testing.T) {
+	base, err := cloudflare.New("somekey", "someemail")
+	if err != nil {
+		t.Fatalf("error building base client: %s", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceCloudflareOriginCACertificate().Schema, map[string]interface{}{})
+
+	client, err := resourceCloudflareOriginCACertificateClient(d, base)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if client != base {
+		t.Fatal("expected the provider-configured client to be reused when api_token is not set")
+	}
+}
+
+func TestResourceCloudflareOriginCACertificateClient_ScopesToAPIToken(t *testing.T) {
+	base, err := cloudflare.New("somekey", "someemail")
+	if err != nil {
+		t.Fatalf("error building base client: %s", err)
+	}
+	base.BaseURL = "https://example.test/client/v4"
+
+	d := schema.TestResourceDataRaw(t, resourceCloudflareOriginCACertificate().Schema, map[string]interface{}{
+		"api_token": "sometoken",
+	})
+
+	client, err := resourceCloudflareOriginCACertificateClient(d, base)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if client == base {
+		t.Fatal("expected a distinct, token-scoped client")
+	}
+
+	if client.BaseURL != base.BaseURL {
+		t.Fatalf("expected scoped client to reuse BaseURL %q, got %q", base.BaseURL, client.BaseURL)
+	}
+}
+
+func TestSetOriginCACertificateParsedAttributes(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating test key: %s", err)
+	}
+
+	notBefore := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		Issuer:       pkix.Name{CommonName: "Cloudflare Origin CA Test"},
+		DNSNames:     []string{"example.com", "www.example.com"},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.Add(90 * 24 * time.Hour),
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("error creating test certificate: %s", err)
+	}
+
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes}))
+
+	cert, err := x509.ParseCertificate(derBytes)
+	if err != nil {
+		t.Fatalf("error parsing test certificate: %s", err)
+	}
+	wantSHA256 := fmt.Sprintf("%x", sha256.Sum256(cert.Raw))
+	wantSHA1 := fmt.Sprintf("%x", sha1.Sum(cert.Raw))
+
+	d := schema.TestResourceDataRaw(t, resourceCloudflareOriginCACertificate().Schema, map[string]interface{}{})
+
+	if err := setOriginCACertificateParsedAttributes(d, certPEM); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := d.Get("serial_number").(string); got != "42" {
+		t.Errorf("expected serial_number %q, got %q", "42", got)
+	}
+	if got := d.Get("sha256_fingerprint").(string); got != wantSHA256 {
+		t.Errorf("expected sha256_fingerprint %q, got %q", wantSHA256, got)
+	}
+	if got := d.Get("sha1_fingerprint").(string); got != wantSHA1 {
+		t.Errorf("expected sha1_fingerprint %q, got %q", wantSHA1, got)
+	}
+	if got := d.Get("not_before").(string); got != notBefore.Format(time.RFC3339) {
+		t.Errorf("expected not_before %q, got %q", notBefore.Format(time.RFC3339), got)
+	}
+
+	dnsNames := d.Get("dns_names").([]interface{})
+	if len(dnsNames) != 2 || dnsNames[0].(string) != "example.com" || dnsNames[1].(string) != "www.example.com" {
+		t.Errorf("expected dns_names [example.com www.example.com], got %v", dnsNames)
+	}
+}
+
+func TestSetOriginCACertificateParsedAttributes_InvalidPEM(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCloudflareOriginCACertificate().Schema, map[string]interface{}{})
+
+	if err := setOriginCACertificateParsedAttributes(d, "not a certificate"); err == nil {
+		t.Fatal("expected an error for invalid PEM data, got nil")
+	}
+}